@@ -2,6 +2,8 @@ package cmd
 
 import (
 	"clitools/internal/image"
+	"clitools/internal/image/backend"
+	"clitools/internal/image/filters"
 	"fmt"
 	"path/filepath"
 	"strings"
@@ -16,6 +18,13 @@ var imageCmd = &cobra.Command{
 Includes cropping transparent areas and converting to WebP format.`,
 }
 
+// newProcessor builds a Processor using the --backend flag shared by all
+// image subcommands: auto (default), go, or vips.
+func newProcessor(cmd *cobra.Command) (*image.Processor, error) {
+	name, _ := cmd.Flags().GetString("backend")
+	return image.NewProcessorWithBackend(backend.Name(name))
+}
+
 var optimizeCmd = &cobra.Command{
 	Use:   "optimize [input] [output]",
 	Short: "Optimize image by cropping transparent areas and converting to WebP",
@@ -24,9 +33,14 @@ var optimizeCmd = &cobra.Command{
 2. Converting to WebP format for better compression
 3. Reducing file size while maintaining quality
 
+Use --alpha-threshold to ignore near-invisible anti-aliased edges (alpha
+1-8) when detecting content, and --padding to keep a transparent margin
+around it.
+
 Examples:
   clitools image optimize input.png output.webp
-  clitools image optimize image.jpg optimized.webp`,
+  clitools image optimize image.jpg optimized.webp
+  clitools image optimize icon.png icon.webp --alpha-threshold 8 --padding 4`,
 	Args: cobra.ExactArgs(2),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		inputPath := args[0]
@@ -45,10 +59,20 @@ Examples:
 
 		quality, _ := cmd.Flags().GetFloat32("quality")
 		svgScale, _ := cmd.Flags().GetFloat32("svg-scale")
+		alphaThreshold, _ := cmd.Flags().GetInt("alpha-threshold")
+		padding, _ := cmd.Flags().GetInt("padding")
+		if alphaThreshold < 0 {
+			return fmt.Errorf("--alpha-threshold must not be negative")
+		}
+		if padding < 0 {
+			return fmt.Errorf("--padding must not be negative")
+		}
 
-		processor := image.NewProcessor()
-		err := processor.OptimizeImageWithScale(inputPath, outputPath, quality, svgScale)
+		processor, err := newProcessor(cmd)
 		if err != nil {
+			return err
+		}
+		if err := processor.OptimizeImageWithOptions(inputPath, outputPath, quality, svgScale, alphaThreshold, padding); err != nil {
 			return fmt.Errorf("failed to optimize image: %w", err)
 		}
 
@@ -65,12 +89,18 @@ var scaleCmd = &cobra.Command{
 - Set specific dimensions: --width 800 --height 600
 - Fit to width/height maintaining aspect ratio: --width 800 or --height 600
 - Resize with different resampling algorithms for quality
+- Control how the image fills the target box with --mode and --anchor:
+  stretch (default, ignores aspect ratio), fit (no cropping, one side may
+  come out smaller than the box), fill/crop (covers the box, crops the
+  excess at --anchor, center by default), pad (fits then pads
+  to the exact size)
 
 Examples:
   clitools image scale input.png output.png --factor 0.5
   clitools image scale input.jpg output.jpg --width 800 --height 600
   clitools image scale input.webp output.webp --width 1200
-  clitools image scale input.svg output.png --height 400 --algorithm lanczos`,
+  clitools image scale input.svg output.png --height 400 --algorithm lanczos
+  clitools image scale input.jpg output.jpg --width 400 --height 400 --mode fill --anchor top`,
 	Args: cobra.ExactArgs(2),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		inputPath := args[0]
@@ -83,6 +113,8 @@ Examples:
 		algorithm, _ := cmd.Flags().GetString("algorithm")
 		quality, _ := cmd.Flags().GetFloat32("quality")
 		svgScale, _ := cmd.Flags().GetFloat32("svg-scale")
+		mode, _ := cmd.Flags().GetString("mode")
+		anchor, _ := cmd.Flags().GetString("anchor")
 
 		// Validate parameters
 		if factor == 0 && width == 0 && height == 0 {
@@ -93,9 +125,11 @@ Examples:
 			return fmt.Errorf("cannot use --factor with --width or --height")
 		}
 
-		processor := image.NewProcessor()
-		err := processor.ScaleImage(inputPath, outputPath, factor, width, height, algorithm, quality, svgScale)
+		processor, err := newProcessor(cmd)
 		if err != nil {
+			return err
+		}
+		if err := processor.ScaleImage(inputPath, outputPath, factor, width, height, algorithm, quality, svgScale, mode, anchor); err != nil {
 			return fmt.Errorf("failed to scale image: %w", err)
 		}
 
@@ -104,13 +138,139 @@ Examples:
 	},
 }
 
+var batchCmd = &cobra.Command{
+	Use:   "batch [input-dir] [output-dir]",
+	Short: "Batch process a directory of images into multiple thumbnail presets",
+	Long: `Recursively walk an input directory, match image files, and produce one
+output variant per preset defined in a YAML config for each match. The output
+directory tree mirrors the input directory tree.
+
+The config file lists one or more presets, each with a width/height, a method
+(scale or crop), an output format, quality, and resampling algorithm. A single
+source image produces N variants, e.g. foo_32x32.webp, foo_96x96.webp.
+
+Example config:
+  presets:
+    - name: 32x32
+      width: 32
+      height: 32
+      method: crop
+      format: .webp
+      quality: 85
+    - name: 96x96
+      width: 96
+      height: 96
+      method: scale
+      format: .webp
+
+Examples:
+  clitools image batch --config presets.yaml ./in ./out
+  clitools image batch --config presets.yaml --concurrency 8 ./in ./out`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		inputDir := args[0]
+		outputDir := args[1]
+
+		configPath, _ := cmd.Flags().GetString("config")
+		concurrency, _ := cmd.Flags().GetInt("concurrency")
+
+		if configPath == "" {
+			return fmt.Errorf("must specify --config with a preset YAML file")
+		}
+
+		cfg, err := image.LoadBatchConfig(configPath)
+		if err != nil {
+			return fmt.Errorf("failed to load config: %w", err)
+		}
+
+		processor, err := newProcessor(cmd)
+		if err != nil {
+			return err
+		}
+		result, err := processor.BatchProcess(inputDir, outputDir, cfg, concurrency)
+		if err != nil {
+			return fmt.Errorf("failed to batch process: %w", err)
+		}
+
+		if len(result.Errors) > 0 {
+			for _, e := range result.Errors {
+				fmt.Printf("error: %v\n", e)
+			}
+			return fmt.Errorf("%d file(s) failed to process", len(result.Errors))
+		}
+
+		return nil
+	},
+}
+
+var filterCmd = &cobra.Command{
+	Use:   "filter [input] [output]",
+	Short: "Apply a chain of post-processing filters to an image",
+	Long: `Apply one or more filters to an image, in the order given, and save the
+result. Each --filter takes a "name[:arg[,arg...]]" token.
+
+Available filters:
+  grayscale                        convert to grayscale
+  invert                           invert colors
+  gaussian-blur:sigma               blur by sigma (default 2)
+  unsharp-mask:sigma,amount,threshold  sharpen (defaults 1,1,0)
+  saturate:pct                      adjust saturation (-100 to 100)
+  brightness:pct                    adjust brightness (-100 to 100)
+  contrast:pct                      adjust contrast (-100 to 100)
+  hue:degrees                       rotate hue
+  sepia:pct                         sepia tone (0-100, default 100)
+  color-balance:r,g,b                per-channel scale (-100 to 100 each)
+
+Examples:
+  clitools image filter input.png output.png --filter grayscale
+  clitools image filter input.jpg output.jpg --filter 'gaussian-blur:3' --filter 'saturate:30'`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		inputPath := args[0]
+		outputPath := args[1]
+
+		tokens, _ := cmd.Flags().GetStringArray("filter")
+		quality, _ := cmd.Flags().GetFloat32("quality")
+		svgScale, _ := cmd.Flags().GetFloat32("svg-scale")
+
+		if len(tokens) == 0 {
+			return fmt.Errorf("must specify at least one --filter")
+		}
+
+		pipeline, err := filters.ParseAll(tokens)
+		if err != nil {
+			return fmt.Errorf("failed to parse filters: %w", err)
+		}
+
+		processor, err := newProcessor(cmd)
+		if err != nil {
+			return err
+		}
+		if err := processor.FilterImage(inputPath, outputPath, pipeline, quality, svgScale); err != nil {
+			return fmt.Errorf("failed to filter image: %w", err)
+		}
+
+		fmt.Printf("Successfully filtered %s -> %s\n", inputPath, outputPath)
+		return nil
+	},
+}
+
 func init() {
 	imageCmd.AddCommand(optimizeCmd)
 	imageCmd.AddCommand(scaleCmd)
+	imageCmd.AddCommand(batchCmd)
+	imageCmd.AddCommand(filterCmd)
+
+	// Add the shared --backend flag to commands that run a Processor
+	for _, c := range []*cobra.Command{optimizeCmd, scaleCmd, filterCmd, batchCmd} {
+		c.Flags().String("backend", "auto", "Processing backend: auto, go, vips (requires a vips build)")
+	}
 
 	// Add flags for optimize command
 	optimizeCmd.Flags().Float32P("quality", "q", 80, "WebP quality (0-100)")
 	optimizeCmd.Flags().Float32P("svg-scale", "s", 2, "SVG rendering scale factor for quality (1-4)")
+	optimizeCmd.Flags().Int("alpha-threshold", 0, "Alpha values at or below this are treated as transparent when cropping (0-255)")
+	optimizeCmd.Flags().Int("padding", 0, "Transparent pixel margin to keep around detected content")
 
 	// Add flags for scale command
 	scaleCmd.Flags().Float32P("factor", "f", 0, "Scale factor (e.g., 0.5 for 50%, 2.0 for 200%)")
@@ -119,4 +279,15 @@ func init() {
 	scaleCmd.Flags().StringP("algorithm", "a", "lanczos", "Resampling algorithm: nearest, bilinear, bicubic, lanczos")
 	scaleCmd.Flags().Float32P("quality", "q", 90, "Output quality for JPEG/WebP (0-100)")
 	scaleCmd.Flags().Float32P("svg-scale", "s", 2, "SVG rendering scale factor for quality (1-4)")
+	scaleCmd.Flags().StringP("mode", "m", "stretch", "Resize mode: stretch, fit, fill, crop, pad")
+	scaleCmd.Flags().String("anchor", "center", "Crop/pad anchor: center, top, bottom, left, right, top-left, top-right, bottom-left, bottom-right")
+
+	// Add flags for batch command
+	batchCmd.Flags().StringP("config", "c", "", "Path to YAML preset config (required)")
+	batchCmd.Flags().IntP("concurrency", "j", 0, "Number of worker goroutines (default: GOMAXPROCS)")
+
+	// Add flags for filter command
+	filterCmd.Flags().StringArray("filter", nil, "Filter to apply, as name[:arg[,arg...]] (repeatable)")
+	filterCmd.Flags().Float32P("quality", "q", 90, "Output quality for JPEG/WebP (0-100)")
+	filterCmd.Flags().Float32P("svg-scale", "s", 2, "SVG rendering scale factor for quality (1-4)")
 }
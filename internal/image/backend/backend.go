@@ -0,0 +1,34 @@
+// Package backend defines the processing engine interface NewProcessor
+// dispatches to. The pure-Go pipeline (imaging + go-webp + oksvg) always
+// ships; a libvips-backed implementation is available when built with the
+// `vips` build tag. See internal/image's backend_vips.go (vips build) and
+// backend_vips_stub.go (default build) for the selection glue.
+//
+// Building with the vips tag requires libvips (and its headers) installed
+// as a system dependency, e.g. `apt-get install libvips-dev` or
+// `brew install vips`; the default build has no such requirement. CI builds
+// both variants so a break in the vips-tagged build doesn't go unnoticed.
+package backend
+
+import "clitools/internal/image/filters"
+
+// Backend implements the image operations exposed by the CLI commands.
+type Backend interface {
+	OptimizeImage(inputPath, outputPath string, quality, svgScale float32, alphaThreshold, padding int) error
+	ScaleImage(inputPath, outputPath string, factor float32, width, height int, algorithm string, quality, svgScale float32, mode, anchor string) error
+	FilterImage(inputPath, outputPath string, pipeline filters.Pipeline, quality, svgScale float32) error
+}
+
+// Name identifies which backend NewProcessorWithBackend should select.
+type Name string
+
+const (
+	// Auto picks libvips when the binary was built with the vips tag,
+	// falling back to the pure-Go pipeline otherwise.
+	Auto Name = "auto"
+	// Go forces the pure-Go pipeline.
+	Go Name = "go"
+	// Vips forces the libvips-backed pipeline, falling back to Go with a
+	// warning if the binary wasn't built with the vips tag.
+	Vips Name = "vips"
+)
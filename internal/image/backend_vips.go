@@ -0,0 +1,215 @@
+//go:build vips
+
+package image
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"clitools/internal/image/backend"
+	"clitools/internal/image/filters"
+
+	"github.com/h2non/bimg"
+)
+
+// vipsBuilt reports whether this binary was compiled with the vips build
+// tag and therefore links libvips via bimg.
+const vipsBuilt = true
+
+// bimgBackend implements backend.Backend on top of libvips via bimg.
+// libvips operations are not free-threaded per image, so concurrent calls
+// are bounded by a semaphore sized to GOMAXPROCS.
+type bimgBackend struct {
+	sem chan struct{}
+}
+
+func newVipsBackend() backend.Backend {
+	return &bimgBackend{sem: make(chan struct{}, runtime.GOMAXPROCS(0))}
+}
+
+func (b *bimgBackend) acquire() func() {
+	b.sem <- struct{}{}
+	return func() { <-b.sem }
+}
+
+// OptimizeImage trims transparent borders and converts to WebP, letting
+// libvips do both the decode and the border trim. alphaThreshold and padding
+// are not supported by bimg's Trim, unlike the go backend's own scan.
+func (b *bimgBackend) OptimizeImage(inputPath, outputPath string, quality, svgScale float32, alphaThreshold, padding int) error {
+	if alphaThreshold != 0 || padding != 0 {
+		return fmt.Errorf("--alpha-threshold and --padding are not supported by the vips backend (use --backend go)")
+	}
+
+	release := b.acquire()
+	defer release()
+
+	data, err := os.ReadFile(inputPath)
+	if err != nil {
+		return fmt.Errorf("failed to read input file: %w", err)
+	}
+
+	trimmed, err := bimg.NewImage(data).Trim()
+	if err != nil {
+		return fmt.Errorf("failed to trim transparent borders: %w", err)
+	}
+
+	out, err := bimg.NewImage(trimmed).Process(bimg.Options{
+		Type:    bimg.WEBP,
+		Quality: int(quality),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to convert to webp: %w", err)
+	}
+
+	return writeOutput(outputPath, out)
+}
+
+// ScaleImage resizes via libvips, which shrinks JPEG/WebP inputs during
+// decode (by a factor of 2, 4, or 8) rather than decoding at full
+// resolution and resizing afterward. svgScale is unused here: libvips
+// rasterizes SVGs itself at whatever target size is requested, so the go
+// backend's supersampling knob doesn't apply. algorithm must be "lanczos"
+// (the default) or empty; libvips always resizes with its own kernel.
+func (b *bimgBackend) ScaleImage(inputPath, outputPath string, factor float32, width, height int, algorithm string, quality, svgScale float32, mode, anchor string) error {
+	release := b.acquire()
+	defer release()
+
+	data, err := os.ReadFile(inputPath)
+	if err != nil {
+		return fmt.Errorf("failed to read input file: %w", err)
+	}
+
+	size, err := bimg.NewImage(data).Size()
+	if err != nil {
+		return fmt.Errorf("failed to read image dimensions: %w", err)
+	}
+
+	resizeMode, err := parseResizeMode(mode)
+	if err != nil {
+		return err
+	}
+
+	if alg := strings.ToLower(algorithm); alg != "" && alg != "lanczos" {
+		return fmt.Errorf("resampling algorithm %q not supported by the vips backend (use --backend go)", algorithm)
+	}
+
+	targetWidth, targetHeight := resolveTarget(size.Width, size.Height, factor, width, height, resizeMode)
+
+	opts := bimg.Options{
+		Width:   targetWidth,
+		Height:  targetHeight,
+		Quality: int(quality),
+		Type:    bimgType(outputPath),
+	}
+
+	switch resizeMode {
+	case ModeStretch:
+		// Force libvips to the exact W x H, matching the go backend's
+		// default behavior of ignoring aspect ratio in stretch mode.
+		opts.Force = true
+	case ModeFill:
+		gravity, err := bimgGravity(anchor)
+		if err != nil {
+			return err
+		}
+		opts.Crop = true
+		opts.Gravity = gravity
+	case ModePad:
+		opts.Embed = true
+		// Formats without an alpha channel (JPEG) get an opaque white
+		// background instead of libvips' default black embed fill, matching
+		// the go backend's choice for the same case.
+		switch strings.ToLower(filepath.Ext(outputPath)) {
+		case ".jpg", ".jpeg":
+			opts.Background = bimg.Color{R: 255, G: 255, B: 255}
+		}
+	}
+
+	out, err := bimg.NewImage(data).Process(opts)
+	if err != nil {
+		return fmt.Errorf("failed to process image: %w", err)
+	}
+
+	return writeOutput(outputPath, out)
+}
+
+// FilterImage maps a subset of the filters pipeline onto libvips operations.
+// Filters without a libvips equivalent return an error asking the caller to
+// use --backend go instead of silently falling back.
+func (b *bimgBackend) FilterImage(inputPath, outputPath string, pipeline filters.Pipeline, quality, svgScale float32) error {
+	release := b.acquire()
+	defer release()
+
+	data, err := os.ReadFile(inputPath)
+	if err != nil {
+		return fmt.Errorf("failed to read input file: %w", err)
+	}
+
+	for _, f := range pipeline {
+		opts := bimg.Options{Quality: int(quality), Type: bimgType(outputPath)}
+
+		switch filter := f.(type) {
+		case filters.Grayscale:
+			opts.Interpretation = bimg.InterpretationBW
+		case filters.GaussianBlur:
+			opts.GaussianBlur = bimg.GaussianBlur{Sigma: filter.Sigma}
+		case filters.Brightness:
+			opts.Brightness = float64(filter.Percentage)
+		default:
+			return fmt.Errorf("filter not supported by the vips backend (use --backend go): %T", f)
+		}
+
+		data, err = bimg.NewImage(data).Process(opts)
+		if err != nil {
+			return fmt.Errorf("failed to apply filter: %w", err)
+		}
+	}
+
+	return writeOutput(outputPath, data)
+}
+
+func writeOutput(outputPath string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(outputPath), 0755); err != nil {
+		return fmt.Errorf("failed to create output directory: %w", err)
+	}
+	if err := os.WriteFile(outputPath, data, 0644); err != nil {
+		return fmt.Errorf("failed to write output file: %w", err)
+	}
+	return nil
+}
+
+// bimgType maps an output path's extension to a bimg.ImageType.
+func bimgType(outputPath string) bimg.ImageType {
+	switch strings.ToLower(filepath.Ext(outputPath)) {
+	case ".png":
+		return bimg.PNG
+	case ".jpg", ".jpeg":
+		return bimg.JPEG
+	default:
+		return bimg.WEBP
+	}
+}
+
+// bimgGravity maps a --anchor value to a bimg.Gravity for crop/fill mode.
+// bimg has no corner gravities, so the four diagonal anchors the go backend
+// supports (top-left, top-right, bottom-left, bottom-right) are rejected
+// rather than silently cropping to center.
+func bimgGravity(anchor string) (bimg.Gravity, error) {
+	switch strings.ToLower(anchor) {
+	case "", "center":
+		return bimg.GravityCentre, nil
+	case "top":
+		return bimg.GravityNorth, nil
+	case "bottom":
+		return bimg.GravitySouth, nil
+	case "left":
+		return bimg.GravityWest, nil
+	case "right":
+		return bimg.GravityEast, nil
+	default:
+		return bimg.GravityCentre, fmt.Errorf("anchor %q not supported by the vips backend for --mode fill (use --backend go, or one of: center, top, bottom, left, right)", anchor)
+	}
+}
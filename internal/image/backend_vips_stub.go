@@ -0,0 +1,15 @@
+//go:build !vips
+
+package image
+
+import "clitools/internal/image/backend"
+
+// vipsBuilt reports whether this binary was compiled with the vips build
+// tag and therefore links libvips via bimg.
+const vipsBuilt = false
+
+// newVipsBackend is unreachable when vipsBuilt is false; NewProcessorWithBackend
+// never calls it in that case.
+func newVipsBackend() backend.Backend {
+	return nil
+}
@@ -0,0 +1,171 @@
+package image
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+)
+
+// batchExtensions lists the source file extensions considered by BatchProcess.
+var batchExtensions = map[string]bool{
+	".png":  true,
+	".jpg":  true,
+	".jpeg": true,
+	".webp": true,
+	".svg":  true,
+}
+
+// BatchResult summarizes the outcome of a batch run.
+type BatchResult struct {
+	FilesProcessed  int
+	VariantsWritten int
+	BytesIn         int64
+	BytesOut        int64
+	Errors          []error
+}
+
+// BytesSaved returns the total reduction in size across all variants, which
+// may be negative if the output ended up larger than the sources.
+func (r *BatchResult) BytesSaved() int64 {
+	return r.BytesIn - r.BytesOut
+}
+
+// batchJob is a single (source file, preset) unit of work.
+type batchJob struct {
+	srcPath string
+	relDir  string
+	size    int64
+	preset  Preset
+}
+
+// batchSVGScale is the supersampling factor used when rasterizing SVG
+// sources for batch presets, which have no per-preset svg-scale field.
+const batchSVGScale = 2
+
+// BatchProcess walks inputDir recursively, matches image files, and produces
+// one output variant per preset in cfg for each match, mirroring the input
+// directory structure under outputDir. Per-file errors are collected rather
+// than aborting the run; concurrency bounds the number of worker goroutines.
+// Each job runs through ScaleImage, so a Processor built with the vips
+// backend processes batch jobs through libvips like every other subcommand.
+func (p *Processor) BatchProcess(inputDir, outputDir string, cfg *BatchConfig, concurrency int) (*BatchResult, error) {
+	if concurrency <= 0 {
+		concurrency = runtime.GOMAXPROCS(0)
+	}
+
+	var sources []struct {
+		path, relDir string
+		size         int64
+	}
+
+	err := filepath.WalkDir(inputDir, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		if !batchExtensions[strings.ToLower(filepath.Ext(path))] {
+			return nil
+		}
+
+		relDir, err := filepath.Rel(inputDir, filepath.Dir(path))
+		if err != nil {
+			return fmt.Errorf("failed to compute relative path for %s: %w", path, err)
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return fmt.Errorf("failed to stat %s: %w", path, err)
+		}
+
+		sources = append(sources, struct {
+			path, relDir string
+			size         int64
+		}{path, relDir, info.Size()})
+
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to walk input directory: %w", err)
+	}
+
+	jobs := make(chan batchJob)
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	result := &BatchResult{}
+
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for job := range jobs {
+				outPath, bytesOut, err := p.runBatchJob(job, outputDir)
+
+				mu.Lock()
+				if err != nil {
+					result.Errors = append(result.Errors, fmt.Errorf("%s (%s): %w", job.srcPath, job.preset.Name, err))
+				} else {
+					// Only count a job's input bytes once it has actually
+					// run, so a job that errors out doesn't inflate
+					// BytesIn with nothing to show for it in
+					// BytesOut/VariantsWritten.
+					result.BytesIn += job.size
+					result.VariantsWritten++
+					result.BytesOut += bytesOut
+					_ = outPath
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+
+	for _, src := range sources {
+		result.FilesProcessed++
+		for _, preset := range cfg.Presets {
+			jobs <- batchJob{srcPath: src.path, relDir: src.relDir, size: src.size, preset: preset}
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	fmt.Printf("Processed %d source files into %d variants\n", result.FilesProcessed, result.VariantsWritten)
+	fmt.Printf("Bytes saved: %.2f KB\n", float64(result.BytesSaved())/1024)
+	if len(result.Errors) > 0 {
+		fmt.Printf("Encountered %d errors\n", len(result.Errors))
+	}
+
+	return result, nil
+}
+
+// runBatchJob produces a single preset variant for a single source file via
+// ScaleImage, so it goes through the Processor's backend (go or vips) like
+// every other image subcommand instead of always running the pure-Go path.
+func (p *Processor) runBatchJob(job batchJob, outputDir string) (string, int64, error) {
+	outDir := filepath.Join(outputDir, job.relDir)
+	if err := os.MkdirAll(outDir, 0755); err != nil {
+		return "", 0, fmt.Errorf("failed to create output directory: %w", err)
+	}
+
+	base := strings.TrimSuffix(filepath.Base(job.srcPath), filepath.Ext(job.srcPath))
+	outPath := filepath.Join(outDir, fmt.Sprintf("%s_%s%s", base, job.preset.Name, job.preset.Format))
+
+	resizeModeName := job.preset.Method
+	if resizeModeName == "scale" {
+		resizeModeName = "stretch"
+	}
+
+	if err := p.ScaleImage(job.srcPath, outPath, 0, job.preset.Width, job.preset.Height, job.preset.Algorithm, job.preset.Quality, batchSVGScale, resizeModeName, "center"); err != nil {
+		return "", 0, err
+	}
+
+	info, err := os.Stat(outPath)
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to stat output file: %w", err)
+	}
+
+	return outPath, info.Size(), nil
+}
@@ -0,0 +1,94 @@
+package image
+
+import (
+	"fmt"
+	"os"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Preset describes a single thumbnail variant to produce from each source image.
+type Preset struct {
+	Name      string  `yaml:"name"`
+	Width     int     `yaml:"width"`
+	Height    int     `yaml:"height"`
+	Method    string  `yaml:"method"` // scale | crop
+	Format    string  `yaml:"format"` // .webp | .jpg | .png
+	Quality   float32 `yaml:"quality"`
+	Algorithm string  `yaml:"algorithm"`
+}
+
+// BatchConfig is the top-level YAML document passed to `clitools image batch --config`.
+type BatchConfig struct {
+	Presets []Preset `yaml:"presets"`
+}
+
+// LoadBatchConfig reads and parses a preset config file for the batch command.
+func LoadBatchConfig(path string) (*BatchConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	var cfg BatchConfig
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse config file: %w", err)
+	}
+
+	if len(cfg.Presets) == 0 {
+		return nil, fmt.Errorf("config file %s defines no presets", path)
+	}
+
+	for i := range cfg.Presets {
+		if err := cfg.Presets[i].normalize(); err != nil {
+			return nil, fmt.Errorf("preset %d: %w", i, err)
+		}
+	}
+
+	type nameFormat struct{ name, format string }
+	seen := make(map[nameFormat]bool, len(cfg.Presets))
+	for _, preset := range cfg.Presets {
+		key := nameFormat{preset.Name, preset.Format}
+		if seen[key] {
+			return nil, fmt.Errorf("duplicate preset: %q produces %s%s for every source file", preset.Name, preset.Name, preset.Format)
+		}
+		seen[key] = true
+	}
+
+	return &cfg, nil
+}
+
+// normalize fills in defaults and validates a preset's fields.
+func (p *Preset) normalize() error {
+	if p.Width <= 0 || p.Height <= 0 {
+		return fmt.Errorf("width and height must be positive")
+	}
+
+	if p.Method == "" {
+		p.Method = "scale"
+	}
+	if p.Method != "scale" && p.Method != "crop" {
+		return fmt.Errorf("unsupported method: %s (use: scale, crop)", p.Method)
+	}
+
+	if p.Format == "" {
+		p.Format = ".webp"
+	}
+	switch p.Format {
+	case ".webp", ".jpg", ".jpeg", ".png":
+	default:
+		return fmt.Errorf("unsupported format: %s (use: .webp, .jpg, .png)", p.Format)
+	}
+
+	if p.Quality == 0 {
+		p.Quality = 90
+	}
+	if p.Algorithm == "" {
+		p.Algorithm = "lanczos"
+	}
+	if p.Name == "" {
+		p.Name = fmt.Sprintf("%dx%d", p.Width, p.Height)
+	}
+
+	return nil
+}
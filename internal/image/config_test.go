@@ -0,0 +1,193 @@
+package image
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestPresetNormalize(t *testing.T) {
+	tests := []struct {
+		name    string
+		preset  Preset
+		wantErr bool
+		check   func(t *testing.T, p Preset)
+	}{
+		{
+			name:   "defaults are filled in",
+			preset: Preset{Width: 32, Height: 32},
+			check: func(t *testing.T, p Preset) {
+				if p.Name != "32x32" {
+					t.Errorf("Name = %q, want %q", p.Name, "32x32")
+				}
+				if p.Method != "scale" {
+					t.Errorf("Method = %q, want %q", p.Method, "scale")
+				}
+				if p.Format != ".webp" {
+					t.Errorf("Format = %q, want %q", p.Format, ".webp")
+				}
+				if p.Quality != 90 {
+					t.Errorf("Quality = %v, want 90", p.Quality)
+				}
+				if p.Algorithm != "lanczos" {
+					t.Errorf("Algorithm = %q, want %q", p.Algorithm, "lanczos")
+				}
+			},
+		},
+		{
+			name:   "explicit fields are preserved",
+			preset: Preset{Name: "thumb", Width: 96, Height: 96, Method: "crop", Format: ".png", Quality: 80, Algorithm: "bicubic"},
+			check: func(t *testing.T, p Preset) {
+				if p.Name != "thumb" || p.Method != "crop" || p.Format != ".png" || p.Quality != 80 || p.Algorithm != "bicubic" {
+					t.Errorf("normalize() changed explicit fields: %+v", p)
+				}
+			},
+		},
+		{
+			name:    "zero width is rejected",
+			preset:  Preset{Width: 0, Height: 32},
+			wantErr: true,
+		},
+		{
+			name:    "negative height is rejected",
+			preset:  Preset{Width: 32, Height: -1},
+			wantErr: true,
+		},
+		{
+			name:    "unsupported method is rejected",
+			preset:  Preset{Width: 32, Height: 32, Method: "resize"},
+			wantErr: true,
+		},
+		{
+			name:    "unsupported format is rejected",
+			preset:  Preset{Width: 32, Height: 32, Format: ".gif"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := tt.preset
+			err := p.normalize()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("normalize() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && tt.check != nil {
+				tt.check(t, p)
+			}
+		})
+	}
+}
+
+func writeConfigFile(t *testing.T, contents string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "presets.yaml")
+	if err := os.WriteFile(path, []byte(contents), 0644); err != nil {
+		t.Fatalf("failed to write test config: %v", err)
+	}
+	return path
+}
+
+func TestLoadBatchConfig(t *testing.T) {
+	t.Run("valid config loads with defaults applied", func(t *testing.T) {
+		path := writeConfigFile(t, `
+presets:
+  - width: 32
+    height: 32
+  - name: large
+    width: 512
+    height: 512
+    format: .jpg
+`)
+		cfg, err := LoadBatchConfig(path)
+		if err != nil {
+			t.Fatalf("LoadBatchConfig() error = %v", err)
+		}
+		if len(cfg.Presets) != 2 {
+			t.Fatalf("len(Presets) = %d, want 2", len(cfg.Presets))
+		}
+		if cfg.Presets[0].Name != "32x32" {
+			t.Errorf("Presets[0].Name = %q, want %q", cfg.Presets[0].Name, "32x32")
+		}
+		if cfg.Presets[1].Format != ".jpg" {
+			t.Errorf("Presets[1].Format = %q, want %q", cfg.Presets[1].Format, ".jpg")
+		}
+	})
+
+	t.Run("missing file errors", func(t *testing.T) {
+		if _, err := LoadBatchConfig(filepath.Join(t.TempDir(), "missing.yaml")); err == nil {
+			t.Fatal("expected error for missing config file, got nil")
+		}
+	})
+
+	t.Run("malformed YAML errors", func(t *testing.T) {
+		path := writeConfigFile(t, "presets: [this is not valid: yaml:")
+		if _, err := LoadBatchConfig(path); err == nil {
+			t.Fatal("expected error for malformed YAML, got nil")
+		}
+	})
+
+	t.Run("empty preset list errors", func(t *testing.T) {
+		path := writeConfigFile(t, "presets: []\n")
+		if _, err := LoadBatchConfig(path); err == nil {
+			t.Fatal("expected error for empty preset list, got nil")
+		}
+	})
+
+	t.Run("invalid preset field errors", func(t *testing.T) {
+		path := writeConfigFile(t, `
+presets:
+  - width: 0
+    height: 32
+`)
+		if _, err := LoadBatchConfig(path); err == nil {
+			t.Fatal("expected error for invalid preset, got nil")
+		}
+	})
+
+	t.Run("duplicate default names for the same dimensions are rejected", func(t *testing.T) {
+		path := writeConfigFile(t, `
+presets:
+  - width: 32
+    height: 32
+  - width: 32
+    height: 32
+    format: .webp
+`)
+		if _, err := LoadBatchConfig(path); err == nil {
+			t.Fatal("expected error for duplicate preset name+format, got nil")
+		}
+	})
+
+	t.Run("duplicate explicit names with the same format are rejected", func(t *testing.T) {
+		path := writeConfigFile(t, `
+presets:
+  - name: thumb
+    width: 32
+    height: 32
+  - name: thumb
+    width: 64
+    height: 64
+`)
+		if _, err := LoadBatchConfig(path); err == nil {
+			t.Fatal("expected error for duplicate preset name+format, got nil")
+		}
+	})
+
+	t.Run("same name with different formats is allowed", func(t *testing.T) {
+		path := writeConfigFile(t, `
+presets:
+  - name: thumb
+    width: 32
+    height: 32
+    format: .webp
+  - name: thumb
+    width: 32
+    height: 32
+    format: .png
+`)
+		if _, err := LoadBatchConfig(path); err != nil {
+			t.Fatalf("LoadBatchConfig() error = %v, want nil", err)
+		}
+	})
+}
@@ -0,0 +1,131 @@
+package image
+
+import (
+	"image"
+	"image/draw"
+)
+
+// alphaReader returns the alpha (0-255) of the pixel at x, y. Resolving the
+// concrete image type once up front lets the scan loops below read Pix
+// directly instead of going through the interface-dispatched, bounds-checked
+// img.At(x, y) on every pixel.
+type alphaReader func(x, y int) uint8
+
+func newAlphaReader(img image.Image) alphaReader {
+	switch im := img.(type) {
+	case *image.RGBA:
+		return func(x, y int) uint8 {
+			return im.Pix[im.PixOffset(x, y)+3]
+		}
+	case *image.NRGBA:
+		return func(x, y int) uint8 {
+			return im.Pix[im.PixOffset(x, y)+3]
+		}
+	default:
+		return func(x, y int) uint8 {
+			_, _, _, a := img.At(x, y).RGBA()
+			return uint8(a >> 8)
+		}
+	}
+}
+
+// cropTransparentAreas removes transparent padding from the image, leaving
+// an optional padding-pixel margin around the detected content. Pixels with
+// alpha <= alphaThreshold are treated as transparent, which lets callers
+// ignore the near-invisible alpha=1..8 fringe anti-aliased edges often leave
+// behind.
+func (p *Processor) cropTransparentAreas(img image.Image, alphaThreshold, padding int) image.Image {
+	bounds := img.Bounds()
+	alphaAt := newAlphaReader(img)
+
+	minX, minY, maxX, maxY, found := findContentBounds(bounds, alphaAt, alphaThreshold)
+	if !found {
+		return image.NewRGBA(image.Rect(0, 0, 1, 1))
+	}
+
+	minX -= padding
+	minY -= padding
+	maxX += padding
+	maxY += padding
+	if minX < bounds.Min.X {
+		minX = bounds.Min.X
+	}
+	if minY < bounds.Min.Y {
+		minY = bounds.Min.Y
+	}
+	if maxX > bounds.Max.X-1 {
+		maxX = bounds.Max.X - 1
+	}
+	if maxY > bounds.Max.Y-1 {
+		maxY = bounds.Max.Y - 1
+	}
+
+	srcRect := image.Rect(minX, minY, maxX+1, maxY+1)
+	croppedImg := image.NewRGBA(image.Rect(0, 0, srcRect.Dx(), srcRect.Dy()))
+	draw.Draw(croppedImg, croppedImg.Bounds(), img, srcRect.Min, draw.Src)
+
+	return croppedImg
+}
+
+// findContentBounds locates the tight bounding box of pixels whose alpha
+// exceeds threshold. It scans rows from the top and bottom until it finds
+// the first non-transparent row on each side, then scans columns left and
+// right, but only within that row range, rather than sweeping every pixel
+// in the image.
+func findContentBounds(bounds image.Rectangle, alphaAt alphaReader, threshold int) (minX, minY, maxX, maxY int, found bool) {
+	minY = bounds.Max.Y
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		if rowHasContent(bounds, alphaAt, y, threshold) {
+			minY = y
+			found = true
+			break
+		}
+	}
+	if !found {
+		return 0, 0, 0, 0, false
+	}
+
+	maxY = bounds.Min.Y
+	for y := bounds.Max.Y - 1; y >= minY; y-- {
+		if rowHasContent(bounds, alphaAt, y, threshold) {
+			maxY = y
+			break
+		}
+	}
+
+	minX = bounds.Max.X
+	for x := bounds.Min.X; x < bounds.Max.X; x++ {
+		if columnHasContent(x, minY, maxY, alphaAt, threshold) {
+			minX = x
+			break
+		}
+	}
+
+	maxX = bounds.Min.X
+	for x := bounds.Max.X - 1; x >= minX; x-- {
+		if columnHasContent(x, minY, maxY, alphaAt, threshold) {
+			maxX = x
+			break
+		}
+	}
+
+	return minX, minY, maxX, maxY, true
+}
+
+func rowHasContent(bounds image.Rectangle, alphaAt alphaReader, y, threshold int) bool {
+	for x := bounds.Min.X; x < bounds.Max.X; x++ {
+		if int(alphaAt(x, y)) > threshold {
+			return true
+		}
+	}
+	return false
+}
+
+func columnHasContent(x, minY, maxY int, alphaAt alphaReader, threshold int) bool {
+	for y := minY; y <= maxY; y++ {
+		if int(alphaAt(x, y)) > threshold {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,119 @@
+package image
+
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+// rgbaWithRect returns an otherwise-transparent RGBA image with an opaque
+// rectangle of the given alpha painted at content.
+func rgbaWithRect(width, height int, content image.Rectangle, alpha uint8) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := content.Min.Y; y < content.Max.Y; y++ {
+		for x := content.Min.X; x < content.Max.X; x++ {
+			img.SetRGBA(x, y, color.RGBA{R: 255, G: 0, B: 0, A: alpha})
+		}
+	}
+	return img
+}
+
+func TestFindContentBounds(t *testing.T) {
+	tests := []struct {
+		name      string
+		img       image.Image
+		threshold int
+		wantMinX  int
+		wantMinY  int
+		wantMaxX  int
+		wantMaxY  int
+		wantFound bool
+	}{
+		{
+			name:      "fully transparent image has no content",
+			img:       rgbaWithRect(10, 10, image.Rect(0, 0, 0, 0), 255),
+			wantFound: false,
+		},
+		{
+			name:     "centered opaque rectangle",
+			img:      rgbaWithRect(10, 10, image.Rect(2, 3, 7, 6), 255),
+			wantMinX: 2, wantMinY: 3, wantMaxX: 6, wantMaxY: 5,
+			wantFound: true,
+		},
+		{
+			name:     "content touching the edges",
+			img:      rgbaWithRect(10, 10, image.Rect(0, 0, 10, 10), 255),
+			wantMinX: 0, wantMinY: 0, wantMaxX: 9, wantMaxY: 9,
+			wantFound: true,
+		},
+		{
+			name:      "anti-aliased fringe below threshold is ignored",
+			img:       rgbaWithRect(10, 10, image.Rect(2, 3, 7, 6), 4),
+			threshold: 8,
+			wantFound: false,
+		},
+		{
+			name:      "anti-aliased fringe above threshold counts as content",
+			img:       rgbaWithRect(10, 10, image.Rect(2, 3, 7, 6), 12),
+			threshold: 8,
+			wantMinX:  2, wantMinY: 3, wantMaxX: 6, wantMaxY: 5,
+			wantFound: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			alphaAt := newAlphaReader(tt.img)
+			minX, minY, maxX, maxY, found := findContentBounds(tt.img.Bounds(), alphaAt, tt.threshold)
+			if found != tt.wantFound {
+				t.Fatalf("found = %v, want %v", found, tt.wantFound)
+			}
+			if !found {
+				return
+			}
+			if minX != tt.wantMinX || minY != tt.wantMinY || maxX != tt.wantMaxX || maxY != tt.wantMaxY {
+				t.Errorf("bounds = (%d, %d, %d, %d), want (%d, %d, %d, %d)",
+					minX, minY, maxX, maxY, tt.wantMinX, tt.wantMinY, tt.wantMaxX, tt.wantMaxY)
+			}
+		})
+	}
+}
+
+func TestCropTransparentAreas(t *testing.T) {
+	p := &Processor{}
+
+	t.Run("crops to content with no padding", func(t *testing.T) {
+		img := rgbaWithRect(20, 20, image.Rect(5, 5, 10, 12), 255)
+		got := p.cropTransparentAreas(img, 0, 0)
+		wantW, wantH := 5, 7
+		if got.Bounds().Dx() != wantW || got.Bounds().Dy() != wantH {
+			t.Errorf("cropped size = %dx%d, want %dx%d", got.Bounds().Dx(), got.Bounds().Dy(), wantW, wantH)
+		}
+	})
+
+	t.Run("padding expands the box on every side", func(t *testing.T) {
+		img := rgbaWithRect(20, 20, image.Rect(5, 5, 10, 12), 255)
+		got := p.cropTransparentAreas(img, 0, 3)
+		wantW, wantH := 11, 13
+		if got.Bounds().Dx() != wantW || got.Bounds().Dy() != wantH {
+			t.Errorf("cropped size = %dx%d, want %dx%d", got.Bounds().Dx(), got.Bounds().Dy(), wantW, wantH)
+		}
+	})
+
+	t.Run("padding near an edge clamps instead of going out of bounds", func(t *testing.T) {
+		img := rgbaWithRect(20, 20, image.Rect(0, 0, 4, 4), 255)
+		got := p.cropTransparentAreas(img, 0, 10)
+		wantW, wantH := 14, 14
+		if got.Bounds().Dx() != wantW || got.Bounds().Dy() != wantH {
+			t.Errorf("cropped size = %dx%d, want %dx%d", got.Bounds().Dx(), got.Bounds().Dy(), wantW, wantH)
+		}
+	})
+
+	t.Run("fully transparent image returns a 1x1 placeholder", func(t *testing.T) {
+		img := rgbaWithRect(20, 20, image.Rect(0, 0, 0, 0), 255)
+		got := p.cropTransparentAreas(img, 0, 0)
+		if got.Bounds().Dx() != 1 || got.Bounds().Dy() != 1 {
+			t.Errorf("placeholder size = %dx%d, want 1x1", got.Bounds().Dx(), got.Bounds().Dy())
+		}
+	})
+}
@@ -0,0 +1,43 @@
+package image
+
+import (
+	"fmt"
+	"image"
+	"path/filepath"
+	"strings"
+
+	"clitools/internal/image/filters"
+)
+
+// FilterImage loads inputPath, applies pipeline in order, and saves the
+// result to outputPath in the format implied by its extension.
+func (p *Processor) FilterImage(inputPath, outputPath string, pipeline filters.Pipeline, quality, svgScale float32) error {
+	if p.backend != nil {
+		return p.backend.FilterImage(inputPath, outputPath, pipeline, quality, svgScale)
+	}
+
+	var img, err = p.loadForFiltering(inputPath, svgScale)
+	if err != nil {
+		return err
+	}
+
+	filtered := pipeline.Apply(img)
+
+	return p.saveImage(filtered, outputPath, quality)
+}
+
+func (p *Processor) loadForFiltering(inputPath string, svgScale float32) (image.Image, error) {
+	if strings.ToLower(filepath.Ext(inputPath)) == ".svg" {
+		img, err := p.loadSVGWithScale(inputPath, svgScale)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load SVG: %w", err)
+		}
+		return img, nil
+	}
+
+	img, err := p.LoadImage(inputPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load image: %w", err)
+	}
+	return img, nil
+}
@@ -0,0 +1,185 @@
+// Package filters implements a composable set of image post-processing
+// effects on top of github.com/disintegration/imaging, used by the
+// `clitools image filter` subcommand.
+package filters
+
+import (
+	"image"
+	"image/color"
+	"math"
+
+	"github.com/disintegration/imaging"
+)
+
+// Filter transforms an image into a new image.
+type Filter interface {
+	Apply(img image.Image) image.Image
+}
+
+// Grayscale converts the image to grayscale.
+type Grayscale struct{}
+
+func (Grayscale) Apply(img image.Image) image.Image {
+	return imaging.Grayscale(img)
+}
+
+// Invert inverts the image's colors.
+type Invert struct{}
+
+func (Invert) Apply(img image.Image) image.Image {
+	return imaging.Invert(img)
+}
+
+// GaussianBlur blurs the image with the given sigma (standard deviation).
+type GaussianBlur struct {
+	Sigma float64
+}
+
+func (f GaussianBlur) Apply(img image.Image) image.Image {
+	return imaging.Blur(img, f.Sigma)
+}
+
+// UnsharpMask sharpens the image by adding amount times the difference
+// between the original and a blurred copy, only where that difference
+// exceeds threshold.
+type UnsharpMask struct {
+	Sigma     float64
+	Amount    float64
+	Threshold float64
+}
+
+func (f UnsharpMask) Apply(img image.Image) image.Image {
+	original := imaging.Clone(img)
+	blurred := imaging.Blur(img, f.Sigma)
+	bounds := original.Bounds()
+	result := image.NewNRGBA(bounds)
+
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			oc := original.NRGBAAt(x, y)
+			bc := blurred.NRGBAAt(x, y)
+
+			result.SetNRGBA(x, y, color.NRGBA{
+				R: unsharpChannel(oc.R, bc.R, f.Amount, f.Threshold),
+				G: unsharpChannel(oc.G, bc.G, f.Amount, f.Threshold),
+				B: unsharpChannel(oc.B, bc.B, f.Amount, f.Threshold),
+				A: oc.A,
+			})
+		}
+	}
+
+	return result
+}
+
+// unsharpChannel boosts a single 8-bit channel by amount times its deviation
+// from the blurred value, but only when that deviation exceeds threshold.
+func unsharpChannel(original, blurred uint8, amount, threshold float64) uint8 {
+	diff := float64(original) - float64(blurred)
+	if math.Abs(diff) <= threshold {
+		return original
+	}
+	return toUint8(float64(original) + diff*amount)
+}
+
+// Saturation adjusts color saturation by pct percent (-100 to 100).
+type Saturation struct {
+	Percentage float64
+}
+
+func (f Saturation) Apply(img image.Image) image.Image {
+	return imaging.AdjustSaturation(img, f.Percentage)
+}
+
+// Brightness adjusts brightness by pct percent (-100 to 100).
+type Brightness struct {
+	Percentage float64
+}
+
+func (f Brightness) Apply(img image.Image) image.Image {
+	return imaging.AdjustBrightness(img, f.Percentage)
+}
+
+// Contrast adjusts contrast by pct percent (-100 to 100).
+type Contrast struct {
+	Percentage float64
+}
+
+func (f Contrast) Apply(img image.Image) image.Image {
+	return imaging.AdjustContrast(img, f.Percentage)
+}
+
+// Hue rotates the hue of every pixel by the given number of degrees.
+type Hue struct {
+	Degrees float64
+}
+
+func (f Hue) Apply(img image.Image) image.Image {
+	return imaging.AdjustFunc(img, func(c color.NRGBA) color.NRGBA {
+		h, s, l := rgbToHSL(c.R, c.G, c.B)
+		h = math.Mod(h+f.Degrees, 360)
+		if h < 0 {
+			h += 360
+		}
+		r, g, b := hslToRGB(h, s, l)
+		return color.NRGBA{R: r, G: g, B: b, A: c.A}
+	})
+}
+
+// Sepia applies a sepia tone at the given strength percent (0-100).
+type Sepia struct {
+	Percentage float64
+}
+
+func (f Sepia) Apply(img image.Image) image.Image {
+	pct := clamp(f.Percentage, 0, 100) / 100
+
+	return imaging.AdjustFunc(img, func(c color.NRGBA) color.NRGBA {
+		r, g, b := float64(c.R), float64(c.G), float64(c.B)
+
+		sr := r*0.393 + g*0.769 + b*0.189
+		sg := r*0.349 + g*0.686 + b*0.168
+		sb := r*0.272 + g*0.534 + b*0.131
+
+		return color.NRGBA{
+			R: toUint8(r + (clamp(sr, 0, 255)-r)*pct),
+			G: toUint8(g + (clamp(sg, 0, 255)-g)*pct),
+			B: toUint8(b + (clamp(sb, 0, 255)-b)*pct),
+			A: c.A,
+		}
+	})
+}
+
+// ColorBalance scales the red, green, and blue channels independently by
+// percent (-100 to 100, where 0 leaves the channel unchanged).
+type ColorBalance struct {
+	R, G, B float64
+}
+
+func (f ColorBalance) Apply(img image.Image) image.Image {
+	rFactor := 1 + clamp(f.R, -100, 100)/100
+	gFactor := 1 + clamp(f.G, -100, 100)/100
+	bFactor := 1 + clamp(f.B, -100, 100)/100
+
+	return imaging.AdjustFunc(img, func(c color.NRGBA) color.NRGBA {
+		return color.NRGBA{
+			R: toUint8(float64(c.R) * rFactor),
+			G: toUint8(float64(c.G) * gFactor),
+			B: toUint8(float64(c.B) * bFactor),
+			A: c.A,
+		}
+	})
+}
+
+func clamp(v, min, max float64) float64 {
+	if v < min {
+		return min
+	}
+	if v > max {
+		return max
+	}
+	return v
+}
+
+func toUint8(v float64) uint8 {
+	return uint8(clamp(v, 0, 255))
+}
@@ -0,0 +1,97 @@
+package filters
+
+import (
+	"fmt"
+	"image"
+	"strconv"
+	"strings"
+)
+
+// Pipeline applies a sequence of filters in order.
+type Pipeline []Filter
+
+// Apply runs every filter in the pipeline in order, feeding each filter's
+// output into the next.
+func (p Pipeline) Apply(img image.Image) image.Image {
+	for _, f := range p {
+		img = f.Apply(img)
+	}
+	return img
+}
+
+// Parse parses a "name[:arg[,arg...]]" token, as passed to repeated
+// --filter flags, into a Filter. Unknown names or malformed arguments
+// produce an error naming the offending filter or argument.
+func Parse(token string) (Filter, error) {
+	name, rawArgs, _ := strings.Cut(token, ":")
+	name = strings.ToLower(strings.TrimSpace(name))
+
+	var args []float64
+	if rawArgs != "" {
+		for _, part := range strings.Split(rawArgs, ",") {
+			v, err := strconv.ParseFloat(strings.TrimSpace(part), 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid argument %q for filter %q: %w", part, name, err)
+			}
+			args = append(args, v)
+		}
+	}
+
+	switch name {
+	case "grayscale", "greyscale":
+		return Grayscale{}, nil
+	case "invert":
+		return Invert{}, nil
+	case "gaussian-blur", "blur":
+		sigma := arg(args, 0, 2)
+		return GaussianBlur{Sigma: sigma}, nil
+	case "unsharp-mask", "sharpen":
+		sigma := arg(args, 0, 1)
+		amount := arg(args, 1, 1)
+		threshold := arg(args, 2, 0)
+		return UnsharpMask{Sigma: sigma, Amount: amount, Threshold: threshold}, nil
+	case "saturate", "saturation":
+		pct := arg(args, 0, 0)
+		return Saturation{Percentage: pct}, nil
+	case "brightness":
+		pct := arg(args, 0, 0)
+		return Brightness{Percentage: pct}, nil
+	case "contrast":
+		pct := arg(args, 0, 0)
+		return Contrast{Percentage: pct}, nil
+	case "hue":
+		deg := arg(args, 0, 0)
+		return Hue{Degrees: deg}, nil
+	case "sepia":
+		pct := arg(args, 0, 100)
+		return Sepia{Percentage: pct}, nil
+	case "color-balance", "colorbalance":
+		r := arg(args, 0, 0)
+		g := arg(args, 1, 0)
+		b := arg(args, 2, 0)
+		return ColorBalance{R: r, G: g, B: b}, nil
+	default:
+		return nil, fmt.Errorf("unknown filter: %s", name)
+	}
+}
+
+// ParseAll parses every token into a Pipeline, in order.
+func ParseAll(tokens []string) (Pipeline, error) {
+	pipeline := make(Pipeline, 0, len(tokens))
+	for _, token := range tokens {
+		f, err := Parse(token)
+		if err != nil {
+			return nil, err
+		}
+		pipeline = append(pipeline, f)
+	}
+	return pipeline, nil
+}
+
+// arg returns args[i] if present, otherwise def.
+func arg(args []float64, i int, def float64) float64 {
+	if i < len(args) {
+		return args[i]
+	}
+	return def
+}
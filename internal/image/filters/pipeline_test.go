@@ -0,0 +1,80 @@
+package filters
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name    string
+		token   string
+		want    Filter
+		wantErr bool
+	}{
+		{name: "grayscale with no args", token: "grayscale", want: Grayscale{}},
+		{name: "greyscale alias", token: "greyscale", want: Grayscale{}},
+		{name: "invert", token: "invert", want: Invert{}},
+		{name: "gaussian-blur with arg", token: "gaussian-blur:3", want: GaussianBlur{Sigma: 3}},
+		{name: "blur alias with default arg", token: "blur", want: GaussianBlur{Sigma: 2}},
+		{name: "unsharp-mask with all args", token: "unsharp-mask:1.5,2,4", want: UnsharpMask{Sigma: 1.5, Amount: 2, Threshold: 4}},
+		{name: "sharpen alias with defaults", token: "sharpen", want: UnsharpMask{Sigma: 1, Amount: 1, Threshold: 0}},
+		{name: "saturate with arg", token: "saturate:30", want: Saturation{Percentage: 30}},
+		{name: "saturation alias", token: "saturation:30", want: Saturation{Percentage: 30}},
+		{name: "brightness with arg", token: "brightness:10", want: Brightness{Percentage: 10}},
+		{name: "contrast with arg", token: "contrast:-5", want: Contrast{Percentage: -5}},
+		{name: "hue with arg", token: "hue:180", want: Hue{Degrees: 180}},
+		{name: "sepia with default", token: "sepia", want: Sepia{Percentage: 100}},
+		{name: "sepia with arg", token: "sepia:50", want: Sepia{Percentage: 50}},
+		{name: "color-balance with all args", token: "color-balance:10,-5,0", want: ColorBalance{R: 10, G: -5, B: 0}},
+		{name: "colorbalance alias", token: "colorbalance:10,-5,0", want: ColorBalance{R: 10, G: -5, B: 0}},
+		{name: "name is case-insensitive and trimmed", token: "  GRAYSCALE  ", want: Grayscale{}},
+		{name: "unknown filter errors", token: "posterize", wantErr: true},
+		{name: "non-numeric argument errors", token: "gaussian-blur:abc", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Parse(tt.token)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Parse(%q) error = %v, wantErr %v", tt.token, err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Parse(%q) = %#v, want %#v", tt.token, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseAll(t *testing.T) {
+	t.Run("builds a pipeline in order", func(t *testing.T) {
+		pipeline, err := ParseAll([]string{"grayscale", "saturate:30"})
+		if err != nil {
+			t.Fatalf("ParseAll() error = %v", err)
+		}
+		want := Pipeline{Grayscale{}, Saturation{Percentage: 30}}
+		if !reflect.DeepEqual(pipeline, want) {
+			t.Errorf("ParseAll() = %#v, want %#v", pipeline, want)
+		}
+	})
+
+	t.Run("stops at the first malformed token", func(t *testing.T) {
+		_, err := ParseAll([]string{"grayscale", "bogus"})
+		if err == nil {
+			t.Fatal("expected error for unknown filter, got nil")
+		}
+	})
+
+	t.Run("empty token list returns an empty pipeline", func(t *testing.T) {
+		pipeline, err := ParseAll(nil)
+		if err != nil {
+			t.Fatalf("ParseAll() error = %v", err)
+		}
+		if len(pipeline) != 0 {
+			t.Errorf("len(pipeline) = %d, want 0", len(pipeline))
+		}
+	})
+}
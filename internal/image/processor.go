@@ -3,25 +3,54 @@ package image
 import (
 	"fmt"
 	"image"
-	"image/color"
 	"image/jpeg"
 	"image/png"
-	"io"
 	"os"
 	"path/filepath"
 	"strings"
 
-	"github.com/disintegration/imaging"
+	"clitools/internal/image/backend"
 	"github.com/kolesa-team/go-webp/encoder"
 	"github.com/kolesa-team/go-webp/webp"
-	"github.com/srwiley/oksvg"
-	"github.com/srwiley/rasterx"
 )
 
-type Processor struct{}
+// Processor runs the pure-Go image pipeline (imaging + go-webp + oksvg)
+// directly, unless backend is set, in which case OptimizeImageWithOptions
+// (and OptimizeImageWithScale), ScaleImage and FilterImage are delegated to
+// it instead (currently only the libvips-backed implementation, built with
+// the `vips` tag). OptimizeImage always runs the pure-Go pipeline.
+type Processor struct {
+	backend backend.Backend
+}
 
+// NewProcessor creates a Processor using the "auto" backend: libvips if this
+// binary was built with the vips tag, otherwise the pure-Go pipeline.
 func NewProcessor() *Processor {
-	return &Processor{}
+	p, _ := NewProcessorWithBackend(backend.Auto)
+	return p
+}
+
+// NewProcessorWithBackend creates a Processor using the given backend. "vips"
+// falls back to the pure-Go pipeline with a warning if this binary wasn't
+// built with the vips tag.
+func NewProcessorWithBackend(name backend.Name) (*Processor, error) {
+	switch name {
+	case "", backend.Auto:
+		if vipsBuilt {
+			return &Processor{backend: newVipsBackend()}, nil
+		}
+		return &Processor{}, nil
+	case backend.Go:
+		return &Processor{}, nil
+	case backend.Vips:
+		if !vipsBuilt {
+			fmt.Println("vips backend not compiled into this binary (rebuild with -tags vips); falling back to the pure-Go pipeline")
+			return &Processor{}, nil
+		}
+		return &Processor{backend: newVipsBackend()}, nil
+	default:
+		return nil, fmt.Errorf("unsupported backend: %s (use: auto, go, vips)", name)
+	}
 }
 
 // OptimizeImage crops transparent areas and converts to WebP format
@@ -50,7 +79,7 @@ func (p *Processor) OptimizeImage(inputPath, outputPath string, quality float32)
 	}
 
 	// Crop transparent areas
-	croppedImg := p.cropTransparentAreas(img)
+	croppedImg := p.cropTransparentAreas(img, 0, 0)
 
 	// Convert to WebP and save
 	return p.saveAsWebP(croppedImg, outputPath, quality)
@@ -58,6 +87,18 @@ func (p *Processor) OptimizeImage(inputPath, outputPath string, quality float32)
 
 // OptimizeImageWithScale crops transparent areas and converts to WebP format with configurable SVG scaling
 func (p *Processor) OptimizeImageWithScale(inputPath, outputPath string, quality, svgScale float32) error {
+	return p.OptimizeImageWithOptions(inputPath, outputPath, quality, svgScale, 0, 0)
+}
+
+// OptimizeImageWithOptions crops transparent areas and converts to WebP format,
+// with configurable SVG scaling, alpha threshold, and crop padding. Pixels
+// with alpha <= alphaThreshold are treated as transparent; padding keeps that
+// many pixels of transparent margin around the detected content.
+func (p *Processor) OptimizeImageWithOptions(inputPath, outputPath string, quality, svgScale float32, alphaThreshold, padding int) error {
+	if p.backend != nil {
+		return p.backend.OptimizeImage(inputPath, outputPath, quality, svgScale, alphaThreshold, padding)
+	}
+
 	var img image.Image
 	var err error
 
@@ -82,64 +123,12 @@ func (p *Processor) OptimizeImageWithScale(inputPath, outputPath string, quality
 	}
 
 	// Crop transparent areas
-	croppedImg := p.cropTransparentAreas(img)
+	croppedImg := p.cropTransparentAreas(img, alphaThreshold, padding)
 
 	// Convert to WebP and save
 	return p.saveAsWebP(croppedImg, outputPath, quality)
 }
 
-// cropTransparentAreas removes transparent padding from the image
-func (p *Processor) cropTransparentAreas(img image.Image) image.Image {
-	bounds := img.Bounds()
-
-	// Find the actual content bounds (non-transparent areas)
-	minX, minY := bounds.Max.X, bounds.Max.Y
-	maxX, maxY := bounds.Min.X, bounds.Min.Y
-
-	foundContent := false
-
-	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
-		for x := bounds.Min.X; x < bounds.Max.X; x++ {
-			r, g, b, a := img.At(x, y).RGBA()
-
-			// Check if pixel is not transparent (alpha > 0) or has color content
-			if a > 0 || r > 0 || g > 0 || b > 0 {
-				if x < minX {
-					minX = x
-				}
-				if x > maxX {
-					maxX = x
-				}
-				if y < minY {
-					minY = y
-				}
-				if y > maxY {
-					maxY = y
-				}
-				foundContent = true
-			}
-		}
-	}
-
-	// If no content found, return a 1x1 transparent image
-	if !foundContent {
-		return image.NewRGBA(image.Rect(0, 0, 1, 1))
-	}
-
-	// Create cropped image
-	croppedBounds := image.Rect(0, 0, maxX-minX+1, maxY-minY+1)
-	croppedImg := image.NewRGBA(croppedBounds)
-
-	// Copy the non-transparent area to the new image
-	for y := minY; y <= maxY; y++ {
-		for x := minX; x <= maxX; x++ {
-			croppedImg.Set(x-minX, y-minY, img.At(x, y))
-		}
-	}
-
-	return croppedImg
-}
-
 // saveAsWebP saves the image as WebP format
 func (p *Processor) saveAsWebP(img image.Image, outputPath string, quality float32) error {
 	// Create output directory if it doesn't exist
@@ -175,158 +164,16 @@ func (p *Processor) saveAsWebP(img image.Image, outputPath string, quality float
 	return nil
 }
 
-// loadSVG loads and rasterizes an SVG file to an image
+// loadSVG loads and rasterizes an SVG file to an image at its natural size,
+// supersampling 2x and downscaling with Lanczos resampling for quality.
 func (p *Processor) loadSVG(path string) (image.Image, error) {
-	// Read SVG file
-	file, err := os.Open(path)
-	if err != nil {
-		return nil, fmt.Errorf("failed to open SVG file: %w", err)
-	}
-	defer file.Close()
-
-	// Read file content
-	svgData, err := io.ReadAll(file)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read SVG file: %w", err)
-	}
-
-	fmt.Printf("Loading SVG: %d bytes\n", len(svgData))
-
-	// Parse SVG
-	icon, err := oksvg.ReadIconStream(strings.NewReader(string(svgData)))
-	if err != nil {
-		return nil, fmt.Errorf("failed to parse SVG: %w", err)
-	}
-
-	// Set reasonable dimensions with higher resolution for quality
-	width, height := icon.ViewBox.W, icon.ViewBox.H
-	if width == 0 || height == 0 {
-		width, height = 512, 512 // Default size for SVGs without dimensions
-	}
-
-	// Scale up for better quality, then we'll scale down during cropping
-	scale := 2.0
-	renderWidth := int(width * scale)
-	renderHeight := int(height * scale)
-
-	fmt.Printf("Rendering SVG at %.0fx%.0f (2x resolution for quality)\n", width, height)
-
-	// Create raster image with transparent background at higher resolution
-	img := image.NewRGBA(image.Rect(0, 0, renderWidth, renderHeight))
-
-	// Initialize with transparent background
-	for y := 0; y < renderHeight; y++ {
-		for x := 0; x < renderWidth; x++ {
-			img.Set(x, y, color.RGBA{0, 0, 0, 0}) // Transparent
-		}
-	}
-
-	// Create scanner and rasterize at higher resolution
-	scanner := rasterx.NewScannerGV(renderWidth, renderHeight, img, img.Bounds())
-	raster := rasterx.NewDasher(renderWidth, renderHeight, scanner)
-
-	// Set viewbox and draw with scaling
-	icon.SetTarget(0, 0, width*scale, height*scale)
-	icon.Draw(raster, 1.0)
-
-	// Scale down for final image if we scaled up
-	if scale != 1.0 {
-		finalImg := image.NewRGBA(image.Rect(0, 0, int(width), int(height)))
-		// Simple downsampling
-		for y := 0; y < int(height); y++ {
-			for x := 0; x < int(width); x++ {
-				srcX := int(float64(x) * scale)
-				srcY := int(float64(y) * scale)
-				if srcX < renderWidth && srcY < renderHeight {
-					finalImg.Set(x, y, img.At(srcX, srcY))
-				}
-			}
-		}
-		img = finalImg
-	}
-
-	fmt.Printf("SVG successfully converted to raster image\n")
-	return img, nil
+	return p.loadSVGAt(path, 0, 0, 2)
 }
 
-// loadSVGWithScale loads and rasterizes an SVG file with configurable scaling
+// loadSVGWithScale loads and rasterizes an SVG file at its natural size,
+// supersampling `scale` times (1-4) and downscaling with Lanczos resampling.
 func (p *Processor) loadSVGWithScale(path string, scale float32) (image.Image, error) {
-	// Read SVG file
-	file, err := os.Open(path)
-	if err != nil {
-		return nil, fmt.Errorf("failed to open SVG file: %w", err)
-	}
-	defer file.Close()
-
-	// Read file content
-	svgData, err := io.ReadAll(file)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read SVG file: %w", err)
-	}
-
-	fmt.Printf("Loading SVG: %d bytes\n", len(svgData))
-
-	// Parse SVG
-	icon, err := oksvg.ReadIconStream(strings.NewReader(string(svgData)))
-	if err != nil {
-		return nil, fmt.Errorf("failed to parse SVG: %w", err)
-	}
-
-	// Set reasonable dimensions with configurable resolution for quality
-	width, height := icon.ViewBox.W, icon.ViewBox.H
-	if width == 0 || height == 0 {
-		width, height = 512, 512 // Default size for SVGs without dimensions
-	}
-
-	// Clamp scale between 1 and 4 for reasonable performance
-	if scale < 1 {
-		scale = 1
-	}
-	if scale > 4 {
-		scale = 4
-	}
-
-	renderWidth := int(width * float64(scale))
-	renderHeight := int(height * float64(scale))
-
-	fmt.Printf("Rendering SVG at %.0fx%.0f (%.1fx scale for quality)\n", width, height, scale)
-
-	// Create raster image with transparent background at higher resolution
-	img := image.NewRGBA(image.Rect(0, 0, renderWidth, renderHeight))
-
-	// Initialize with transparent background
-	for y := 0; y < renderHeight; y++ {
-		for x := 0; x < renderWidth; x++ {
-			img.Set(x, y, color.RGBA{0, 0, 0, 0}) // Transparent
-		}
-	}
-
-	// Create scanner and rasterize at higher resolution
-	scanner := rasterx.NewScannerGV(renderWidth, renderHeight, img, img.Bounds())
-	raster := rasterx.NewDasher(renderWidth, renderHeight, scanner)
-
-	// Set viewbox and draw with scaling
-	icon.SetTarget(0, 0, width*float64(scale), height*float64(scale))
-	icon.Draw(raster, 1.0)
-
-	// Scale down for final image if we scaled up
-	if scale != 1.0 {
-		finalImg := image.NewRGBA(image.Rect(0, 0, int(width), int(height)))
-		// Simple downsampling
-		for y := 0; y < int(height); y++ {
-			for x := 0; x < int(width); x++ {
-				srcX := int(float64(x) * float64(scale))
-				srcY := int(float64(y) * float64(scale))
-				if srcX < renderWidth && srcY < renderHeight {
-					finalImg.Set(x, y, img.At(srcX, srcY))
-				}
-			}
-		}
-		img = finalImg
-	}
-
-	fmt.Printf("SVG successfully converted to raster image\n")
-	return img, nil
+	return p.loadSVGAt(path, 0, 0, scale)
 }
 
 // LoadImage loads an image from file, supporting various formats including SVG
@@ -357,67 +204,74 @@ func (p *Processor) LoadImage(path string) (image.Image, error) {
 	}
 }
 
-// ScaleImage scales an image using various methods and saves it
-func (p *Processor) ScaleImage(inputPath, outputPath string, factor float32, width, height int, algorithm string, quality, svgScale float32) error {
-	var img image.Image
-	var err error
+// ScaleImage scales an image using various methods and saves it. mode
+// controls how the image is fitted into the resolved width/height box
+// (stretch, fit, fill/crop, pad); anchor controls the crop/pad position.
+func (p *Processor) ScaleImage(inputPath, outputPath string, factor float32, width, height int, algorithm string, quality, svgScale float32, mode, anchor string) error {
+	if p.backend != nil {
+		return p.backend.ScaleImage(inputPath, outputPath, factor, width, height, algorithm, quality, svgScale, mode, anchor)
+	}
 
-	// Load the image
-	if strings.ToLower(filepath.Ext(inputPath)) == ".svg" {
-		img, err = p.loadSVGWithScale(inputPath, svgScale)
+	resizeMode, err := parseResizeMode(mode)
+	if err != nil {
+		return err
+	}
+
+	anchorPoint, err := parseAnchor(anchor)
+	if err != nil {
+		return err
+	}
+
+	filter, ok := algorithmFilter(algorithm)
+	if !ok {
+		return fmt.Errorf("unsupported resampling algorithm: %s (use: nearest, bilinear, bicubic, lanczos)", algorithm)
+	}
+
+	isSVG := strings.ToLower(filepath.Ext(inputPath)) == ".svg"
+
+	var originalWidth, originalHeight int
+	if isSVG {
+		w, h, err := svgDimensions(inputPath)
 		if err != nil {
-			return fmt.Errorf("failed to load SVG: %w", err)
+			return fmt.Errorf("failed to read SVG dimensions: %w", err)
+		}
+		originalWidth, originalHeight = int(w), int(h)
+	}
+
+	var img image.Image
+	var scaledImg image.Image
+
+	if isSVG {
+		targetWidth, targetHeight := resolveTarget(originalWidth, originalHeight, factor, width, height, resizeMode)
+		fmt.Printf("Scaling from %dx%d to %dx%d (mode: %s)\n", originalWidth, originalHeight, targetWidth, targetHeight, resizeMode)
+
+		if resizeMode == ModeStretch {
+			// Rasterize directly at the target resolution; no intermediate
+			// resize needed since the renderer can target any size exactly.
+			img, err = p.loadSVGAt(inputPath, targetWidth, targetHeight, svgScale)
+			if err != nil {
+				return fmt.Errorf("failed to load SVG: %w", err)
+			}
+			scaledImg = img
+		} else {
+			img, err = p.loadSVGWithScale(inputPath, svgScale)
+			if err != nil {
+				return fmt.Errorf("failed to load SVG: %w", err)
+			}
+			scaledImg = applyResizeMode(img, targetWidth, targetHeight, resizeMode, anchorPoint, filter, outputPath)
 		}
 	} else {
 		img, err = p.LoadImage(inputPath)
 		if err != nil {
 			return fmt.Errorf("failed to load image: %w", err)
 		}
-	}
-
-	// Get original dimensions
-	bounds := img.Bounds()
-	originalWidth := bounds.Dx()
-	originalHeight := bounds.Dy()
-
-	var targetWidth, targetHeight int
-
-	// Calculate target dimensions
-	if factor != 0 {
-		// Scale by factor
-		targetWidth = int(float32(originalWidth) * factor)
-		targetHeight = int(float32(originalHeight) * factor)
-	} else if width != 0 && height != 0 {
-		// Both dimensions specified
-		targetWidth = width
-		targetHeight = height
-	} else if width != 0 {
-		// Only width specified, maintain aspect ratio
-		aspectRatio := float32(originalHeight) / float32(originalWidth)
-		targetWidth = width
-		targetHeight = int(float32(width) * aspectRatio)
-	} else if height != 0 {
-		// Only height specified, maintain aspect ratio
-		aspectRatio := float32(originalWidth) / float32(originalHeight)
-		targetHeight = height
-		targetWidth = int(float32(height) * aspectRatio)
-	}
 
-	fmt.Printf("Scaling from %dx%d to %dx%d\n", originalWidth, originalHeight, targetWidth, targetHeight)
+		bounds := img.Bounds()
+		originalWidth, originalHeight = bounds.Dx(), bounds.Dy()
+		targetWidth, targetHeight := resolveTarget(originalWidth, originalHeight, factor, width, height, resizeMode)
+		fmt.Printf("Scaling from %dx%d to %dx%d (mode: %s)\n", originalWidth, originalHeight, targetWidth, targetHeight, resizeMode)
 
-	// Scale the image using the specified algorithm
-	var scaledImg image.Image
-	switch strings.ToLower(algorithm) {
-	case "nearest":
-		scaledImg = imaging.Resize(img, targetWidth, targetHeight, imaging.NearestNeighbor)
-	case "bilinear", "linear":
-		scaledImg = imaging.Resize(img, targetWidth, targetHeight, imaging.Linear)
-	case "bicubic", "cubic":
-		scaledImg = imaging.Resize(img, targetWidth, targetHeight, imaging.CatmullRom)
-	case "lanczos":
-		scaledImg = imaging.Resize(img, targetWidth, targetHeight, imaging.Lanczos)
-	default:
-		return fmt.Errorf("unsupported resampling algorithm: %s (use: nearest, bilinear, bicubic, lanczos)", algorithm)
+		scaledImg = applyResizeMode(img, targetWidth, targetHeight, resizeMode, anchorPoint, filter, outputPath)
 	}
 
 	// Save the scaled image
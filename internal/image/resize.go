@@ -0,0 +1,166 @@
+package image
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"path/filepath"
+	"strings"
+
+	"github.com/disintegration/imaging"
+)
+
+// ResizeMode controls how an image is fitted into a target width/height box.
+type ResizeMode string
+
+const (
+	// ModeStretch scales directly to the target dimensions, ignoring aspect ratio.
+	ModeStretch ResizeMode = "stretch"
+	// ModeFit scales to fit inside the box while preserving aspect ratio; one
+	// dimension of the result may end up smaller than the box.
+	ModeFit ResizeMode = "fit"
+	// ModeFill scales to cover the box, then crops the excess at the anchor
+	// (center by default).
+	ModeFill ResizeMode = "fill"
+	// ModePad fits the image inside the box, then pads to the exact size.
+	ModePad ResizeMode = "pad"
+)
+
+// parseResizeMode normalizes a --mode flag value, treating "crop" as an alias for fill.
+func parseResizeMode(mode string) (ResizeMode, error) {
+	switch strings.ToLower(mode) {
+	case "", "stretch":
+		return ModeStretch, nil
+	case "fit":
+		return ModeFit, nil
+	case "fill", "crop":
+		return ModeFill, nil
+	case "pad":
+		return ModePad, nil
+	default:
+		return "", fmt.Errorf("unsupported resize mode: %s (use: stretch, fit, fill, crop, pad)", mode)
+	}
+}
+
+// parseAnchor maps a --anchor flag value to an imaging.Anchor constant.
+func parseAnchor(anchor string) (imaging.Anchor, error) {
+	switch strings.ToLower(anchor) {
+	case "", "center":
+		return imaging.Center, nil
+	case "top":
+		return imaging.Top, nil
+	case "bottom":
+		return imaging.Bottom, nil
+	case "left":
+		return imaging.Left, nil
+	case "right":
+		return imaging.Right, nil
+	case "top-left":
+		return imaging.TopLeft, nil
+	case "top-right":
+		return imaging.TopRight, nil
+	case "bottom-left":
+		return imaging.BottomLeft, nil
+	case "bottom-right":
+		return imaging.BottomRight, nil
+	default:
+		return imaging.Center, fmt.Errorf("unsupported anchor: %s (use: center, top, bottom, left, right, top-left, top-right, bottom-left, bottom-right)", anchor)
+	}
+}
+
+// algorithmFilter maps a --algorithm flag value to an imaging.ResampleFilter,
+// reporting false if the name is not recognized.
+func algorithmFilter(algorithm string) (imaging.ResampleFilter, bool) {
+	switch strings.ToLower(algorithm) {
+	case "nearest":
+		return imaging.NearestNeighbor, true
+	case "bilinear", "linear":
+		return imaging.Linear, true
+	case "bicubic", "cubic":
+		return imaging.CatmullRom, true
+	case "lanczos":
+		return imaging.Lanczos, true
+	default:
+		return imaging.Lanczos, false
+	}
+}
+
+// resolveTarget computes the output dimensions for ScaleImage given the
+// original size and the factor/width/height flags a caller may supply.
+// The resize mode does not change which dimensions are resolved here; it
+// only changes how the image is later fitted into the resolved box.
+func resolveTarget(originalWidth, originalHeight int, factor float32, width, height int, mode ResizeMode) (int, int) {
+	switch {
+	case factor != 0:
+		return int(float32(originalWidth) * factor), int(float32(originalHeight) * factor)
+	case width != 0 && height != 0:
+		return width, height
+	case width != 0:
+		aspectRatio := float32(originalHeight) / float32(originalWidth)
+		return width, int(float32(width) * aspectRatio)
+	case height != 0:
+		aspectRatio := float32(originalWidth) / float32(originalHeight)
+		return int(float32(height) * aspectRatio), height
+	default:
+		return originalWidth, originalHeight
+	}
+}
+
+// applyResizeMode fits img into a targetWidth x targetHeight box according to mode.
+// outputPath determines the ModePad canvas background: transparent for formats
+// that support an alpha channel, opaque white for formats that don't (JPEG
+// ignores alpha, so a transparent canvas would otherwise encode as black bars).
+func applyResizeMode(img image.Image, targetWidth, targetHeight int, mode ResizeMode, anchor imaging.Anchor, filter imaging.ResampleFilter, outputPath string) image.Image {
+	switch mode {
+	case ModeFit:
+		return imaging.Fit(img, targetWidth, targetHeight, filter)
+	case ModeFill:
+		return imaging.Fill(img, targetWidth, targetHeight, anchor, filter)
+	case ModePad:
+		fitted := imaging.Fit(img, targetWidth, targetHeight, filter)
+		canvas := imaging.New(targetWidth, targetHeight, padBackground(outputPath))
+		pos := anchorPastePosition(anchor, targetWidth, targetHeight, fitted.Bounds().Dx(), fitted.Bounds().Dy())
+		return imaging.Paste(canvas, fitted, pos)
+	default:
+		return imaging.Resize(img, targetWidth, targetHeight, filter)
+	}
+}
+
+// padBackground picks the ModePad canvas fill color for an output path's format.
+func padBackground(outputPath string) color.Color {
+	switch strings.ToLower(filepath.Ext(outputPath)) {
+	case ".jpg", ".jpeg":
+		return color.White
+	default:
+		return color.Transparent
+	}
+}
+
+// anchorPastePosition returns the top-left point at which an imgWidth x
+// imgHeight image should be pasted onto a canvasWidth x canvasHeight canvas
+// for the given anchor.
+func anchorPastePosition(anchor imaging.Anchor, canvasWidth, canvasHeight, imgWidth, imgHeight int) image.Point {
+	x := (canvasWidth - imgWidth) / 2
+	y := (canvasHeight - imgHeight) / 2
+
+	switch anchor {
+	case imaging.Top:
+		y = 0
+	case imaging.Bottom:
+		y = canvasHeight - imgHeight
+	case imaging.Left:
+		x = 0
+	case imaging.Right:
+		x = canvasWidth - imgWidth
+	case imaging.TopLeft:
+		x, y = 0, 0
+	case imaging.TopRight:
+		x, y = canvasWidth-imgWidth, 0
+	case imaging.BottomLeft:
+		x, y = 0, canvasHeight-imgHeight
+	case imaging.BottomRight:
+		x, y = canvasWidth-imgWidth, canvasHeight-imgHeight
+	}
+
+	return image.Point{X: x, Y: y}
+}
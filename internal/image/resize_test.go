@@ -0,0 +1,109 @@
+package image
+
+import (
+	"image"
+	"testing"
+
+	"github.com/disintegration/imaging"
+)
+
+func TestResolveTarget(t *testing.T) {
+	tests := []struct {
+		name                          string
+		originalWidth, originalHeight int
+		factor                        float32
+		width, height                 int
+		mode                          ResizeMode
+		wantWidth, wantHeight         int
+	}{
+		{
+			name: "factor scales both dimensions", originalWidth: 100, originalHeight: 50,
+			factor: 2, wantWidth: 200, wantHeight: 100,
+		},
+		{
+			name: "explicit width and height win over aspect ratio", originalWidth: 100, originalHeight: 50,
+			width: 40, height: 40, wantWidth: 40, wantHeight: 40,
+		},
+		{
+			name: "width only preserves aspect ratio", originalWidth: 200, originalHeight: 100,
+			width: 50, wantWidth: 50, wantHeight: 25,
+		},
+		{
+			name: "height only preserves aspect ratio", originalWidth: 200, originalHeight: 100,
+			height: 25, wantWidth: 50, wantHeight: 25,
+		},
+		{
+			name: "no flags set returns original size", originalWidth: 100, originalHeight: 50,
+			wantWidth: 100, wantHeight: 50,
+		},
+		{
+			name: "mode does not change which dimensions are resolved", originalWidth: 200, originalHeight: 100,
+			width: 50, mode: ModeFill, wantWidth: 50, wantHeight: 25,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotWidth, gotHeight := resolveTarget(tt.originalWidth, tt.originalHeight, tt.factor, tt.width, tt.height, tt.mode)
+			if gotWidth != tt.wantWidth || gotHeight != tt.wantHeight {
+				t.Errorf("resolveTarget() = (%d, %d), want (%d, %d)", gotWidth, gotHeight, tt.wantWidth, tt.wantHeight)
+			}
+		})
+	}
+}
+
+func TestAnchorPastePosition(t *testing.T) {
+	const canvasWidth, canvasHeight = 100, 80
+	const imgWidth, imgHeight = 40, 20
+
+	tests := []struct {
+		anchor imaging.Anchor
+		wantX  int
+		wantY  int
+	}{
+		{imaging.Center, 30, 30},
+		{imaging.Top, 30, 0},
+		{imaging.Bottom, 30, 60},
+		{imaging.Left, 0, 30},
+		{imaging.Right, 60, 30},
+		{imaging.TopLeft, 0, 0},
+		{imaging.TopRight, 60, 0},
+		{imaging.BottomLeft, 0, 60},
+		{imaging.BottomRight, 60, 60},
+	}
+
+	for _, tt := range tests {
+		got := anchorPastePosition(tt.anchor, canvasWidth, canvasHeight, imgWidth, imgHeight)
+		want := image.Point{X: tt.wantX, Y: tt.wantY}
+		if got != want {
+			t.Errorf("anchorPastePosition(%v) = %v, want %v", tt.anchor, got, want)
+		}
+	}
+}
+
+func TestParseResizeMode(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    ResizeMode
+		wantErr bool
+	}{
+		{"", ModeStretch, false},
+		{"stretch", ModeStretch, false},
+		{"fit", ModeFit, false},
+		{"fill", ModeFill, false},
+		{"crop", ModeFill, false},
+		{"pad", ModePad, false},
+		{"bogus", "", true},
+	}
+
+	for _, tt := range tests {
+		got, err := parseResizeMode(tt.input)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("parseResizeMode(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("parseResizeMode(%q) = %q, want %q", tt.input, got, tt.want)
+		}
+	}
+}